@@ -3,6 +3,8 @@ package tea
 import (
 	"io"
 	"os/exec"
+	"sync"
+	"time"
 )
 
 // execMsg is used internally to run an ExecCommand sent with Exec.
@@ -45,6 +47,11 @@ func Exec(c ExecCommand, fn ExecCallback) Cmd {
 //
 //	cmd := ExecProcess(exec.Command("vim", "file.txt"), nil)
 //
+// ExecProcess wires the child's stdio through plain pipes, so programs that
+// gate behavior on isatty checks (less, git, vim's color heuristics) may not
+// behave as they would from a real shell. If that matters, use
+// ExecProcessPTY instead.
+//
 // For non-interactive i/o you should use a Cmd (that is, a tea.Cmd).
 func ExecProcess(c *exec.Cmd, stdinProxy ReaderProxy, stdoutProxy WriterProxy, stderrProxy WriterProxy, fn ExecCallback) Cmd {
 	return Exec(wrapExecCommand(c, stdinProxy, stdoutProxy, stderrProxy), fn)
@@ -68,11 +75,36 @@ type ReaderProxy struct {
 	io.Reader
 	From    io.Reader
 	Handler func(b []byte, n int, err error)
+
+	// Extra mirrors each chunk read from From to additional sinks (a log
+	// file, a ring buffer, whatever) without the caller having to
+	// reimplement the io.Reader contract. Write failures here don't fail
+	// the Read; they're collected into a MultiError and folded into the
+	// error Handler sees.
+	Extra []io.Writer
+
+	// ByteScanner, if set, splits the stream into tokens (lines by
+	// default) before calling Handler, so Handler sees whole tokens
+	// instead of raw, possibly-partial Read chunks.
+	ByteScanner *ByteScanner
 }
 
 func (s ReaderProxy) Read(b []byte) (n int, err error) {
 	n, err = s.From.Read(b)
-	s.Handler(b, n, err)
+
+	handlerErr := err
+	if n > 0 && len(s.Extra) > 0 {
+		if extraErr := teeTo(s.Extra, b[:n]); extraErr != nil {
+			handlerErr = joinErrs(err, extraErr)
+		}
+	}
+
+	if s.ByteScanner != nil {
+		s.ByteScanner.feed(b[:n], handlerErr, s.Handler)
+	} else {
+		s.Handler(b, n, handlerErr)
+	}
+
 	return n, err
 }
 
@@ -81,11 +113,34 @@ type WriterProxy struct {
 	io.Writer
 	From    io.Writer
 	Handler func(b []byte, n int, err error)
+
+	// Extra mirrors each chunk written to From to additional sinks. Write
+	// failures here don't fail the Write; they're collected into a
+	// MultiError and folded into the error Handler sees.
+	Extra []io.Writer
+
+	// ByteScanner, if set, splits the stream into tokens (lines by
+	// default) before calling Handler, so Handler sees whole tokens
+	// instead of raw, possibly-partial Write chunks.
+	ByteScanner *ByteScanner
 }
 
 func (s WriterProxy) Write(b []byte) (n int, err error) {
-	s.Handler(b, n, err)
 	n, err = s.From.Write(b)
+
+	handlerErr := err
+	if n > 0 && len(s.Extra) > 0 {
+		if extraErr := teeTo(s.Extra, b[:n]); extraErr != nil {
+			handlerErr = joinErrs(err, extraErr)
+		}
+	}
+
+	if s.ByteScanner != nil {
+		s.ByteScanner.feed(b[:n], handlerErr, s.Handler)
+	} else {
+		s.Handler(b, n, handlerErr)
+	}
+
 	return n, err
 }
 
@@ -107,12 +162,33 @@ type OsExecCommand struct {
 	StdinProxy  ReaderProxy
 	StdoutProxy WriterProxy
 	StderrProxy WriterProxy
+
+	// IORelayTimeout bounds how long Run waits for the stdin/stdout/stderr
+	// copy loops to finish once the child has exited. Defaults to
+	// defaultIORelayTimeout when zero.
+	IORelayTimeout time.Duration
+
+	// startMu guards c.Cmd.Process against the race between Run's call to
+	// Cmd.Start and ContextExecCommand.supervise reading Process from a
+	// different goroutine; see startedCmd.
+	startMu sync.Mutex
 }
 
 func (c *OsExecCommand) GetProxies() (ReaderProxy, WriterProxy, WriterProxy) {
 	return c.StdinProxy, c.StdoutProxy, c.StderrProxy
 }
 
+// startedCmd gives ContextExecCommand access to the underlying *exec.Cmd so
+// it can signal the process on context cancellation, reporting whether
+// Cmd.Start has actually run yet. It's synchronized against the same lock
+// Run holds while starting the process, so supervise never reads Process
+// while Start is still writing it.
+func (c *OsExecCommand) startedCmd() (*exec.Cmd, bool) {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+	return c.Cmd, c.Cmd.Process != nil
+}
+
 // SetStdin sets stdin on underlying exec.Cmd to the given io.Reader.
 func (c *OsExecCommand) SetStdin(r io.Reader) {
 	// If unset, have the command use the same input as the terminal.
@@ -147,27 +223,45 @@ func (p *Program) exec(c ExecCommand, fn ExecCallback) {
 		return
 	}
 
-	stdinProxy, stdoutProxy, stderrProxy := c.GetProxies()
-	stdinProxy.From = p.input
-	stdoutProxy.From = p.output
-	stderrProxy.From = p.output
+	pc, wantsPty := c.(ptyExecCommand)
+	if wantsPty {
+		// A wrapper such as ContextExecCommand always has a RunPty method
+		// (forwarding when its wrapped command supports one), so having one
+		// doesn't by itself mean this command wants the pty path: consult
+		// ptyAware, when present, to find out what the wrapped command
+		// actually is.
+		if pa, ok := c.(ptyAware); ok {
+			wantsPty = pa.usesPty()
+		}
+	}
 
-	c.SetStdin(stdinProxy)
-	c.SetStdout(stdoutProxy)
-	c.SetStderr(stderrProxy)
+	var runErr error
+	if wantsPty {
+		// The command wants raw pty setup (ExecProcessPTY) rather than the
+		// plain pipe wiring below, so hand it the program's real input and
+		// output directly and let it manage its own stdio.
+		runErr = pc.RunPty(p.input, p.output)
+	} else {
+		stdinProxy, stdoutProxy, stderrProxy := c.GetProxies()
+		stdinProxy.From = p.input
+		stdoutProxy.From = p.output
+		stderrProxy.From = p.output
 
-	// Execute system command.
-	if err := c.Run(); err != nil {
-		_ = p.RestoreTerminal() // also try to restore the terminal.
-		if fn != nil {
-			go p.Send(fn(err))
-		}
-		return
+		c.SetStdin(stdinProxy)
+		c.SetStdout(stdoutProxy)
+		c.SetStderr(stderrProxy)
+
+		runErr = c.Run()
 	}
 
 	// Have the program re-capture input.
-	err := p.RestoreTerminal()
+	restoreErr := p.RestoreTerminal()
+
 	if fn != nil {
-		go p.Send(fn(err))
+		if runErr != nil {
+			go p.Send(fn(runErr))
+		} else {
+			go p.Send(fn(restoreErr))
+		}
 	}
 }
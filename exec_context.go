@@ -0,0 +1,183 @@
+package tea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// defaultGracePeriod is how long ExecContext waits after sending SIGINT
+// before escalating to SIGKILL, unless ContextExecCommand.GracePeriod says
+// otherwise.
+const defaultGracePeriod = 5 * time.Second
+
+// ExecContext wraps an ExecCommand so that, when run through Program.exec,
+// the command is interrupted if ctx is canceled or its deadline expires:
+// the child is sent SIGINT, and if it hasn't exited after GracePeriod,
+// SIGKILL. Program.exec still restores the terminal and delivers the
+// result to fn either way, wrapping context.DeadlineExceeded or
+// context.Canceled so a hung child can't block the Program's shutdown path
+// forever.
+//
+// Use ExitStateFromError on the error fn receives to get the exit code,
+// whether the child was signaled, and whether it timed out, without
+// type-asserting *exec.ExitError yourself.
+func ExecContext(ctx context.Context, c ExecCommand, fn ExecCallback) Cmd {
+	return Exec(&ContextExecCommand{ExecCommand: c, ctx: ctx}, fn)
+}
+
+// ExecProcessContext is the context-aware counterpart to ExecProcess: it
+// runs c under ctx, tearing the child down with SIGINT then SIGKILL if ctx
+// is canceled or its deadline expires before the command exits.
+func ExecProcessContext(ctx context.Context, c *exec.Cmd, stdinProxy ReaderProxy, stdoutProxy WriterProxy, stderrProxy WriterProxy, fn ExecCallback) Cmd {
+	return ExecContext(ctx, wrapExecCommand(c, stdinProxy, stdoutProxy, stderrProxy), fn)
+}
+
+// cmdAccessor is implemented by the built-in ExecCommand wrappers
+// (OsExecCommand, PtyExecCommand) so ContextExecCommand can reach the
+// underlying *exec.Cmd to signal it on cancellation. startedCmd reports
+// whether the wrapped command has actually called Start yet, synchronized
+// against the same lock it holds while doing so, so supervise never reads
+// Process while it's concurrently being set.
+type cmdAccessor interface {
+	startedCmd() (*exec.Cmd, bool)
+}
+
+// ContextExecCommand wraps another ExecCommand, running it under ctx and
+// escalating from SIGINT to SIGKILL if ctx is canceled or times out before
+// the wrapped command finishes. Build one with ExecContext or
+// ExecProcessContext rather than directly.
+type ContextExecCommand struct {
+	ExecCommand
+	ctx context.Context
+
+	// GracePeriod is how long to wait after SIGINT before sending SIGKILL.
+	// Defaults to 5 seconds when zero.
+	GracePeriod time.Duration
+}
+
+// Run executes the wrapped command, watching ctx for cancellation.
+func (c *ContextExecCommand) Run() error {
+	return c.supervise(c.ExecCommand.Run)
+}
+
+// usesPty reports whether the wrapped command wants raw pty setup, so
+// Program.exec doesn't take the pty branch for a ContextExecCommand whose
+// wrapped command doesn't actually support it.
+func (c *ContextExecCommand) usesPty() bool {
+	_, ok := c.ExecCommand.(ptyExecCommand)
+	return ok
+}
+
+// RunPty forwards to the wrapped command's RunPty, if it has one, applying
+// the same ctx-driven SIGINT/SIGKILL escalation as Run.
+func (c *ContextExecCommand) RunPty(input io.Reader, output io.Writer) error {
+	pc, ok := c.ExecCommand.(ptyExecCommand)
+	if !ok {
+		return fmt.Errorf("tea: ExecContext: %T does not support running under a pty", c.ExecCommand)
+	}
+	return c.supervise(func() error {
+		return pc.RunPty(input, output)
+	})
+}
+
+// supervise runs run in a goroutine and watches ctx: if it's canceled or
+// times out before run returns, the child is sent SIGINT, then SIGKILL
+// after GracePeriod.
+func (c *ContextExecCommand) supervise(run func() error) error {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	cmd, started := c.cmd()
+	if !started {
+		// Either the wrapped command doesn't expose an *exec.Cmd, or it
+		// hasn't called Start yet; either way there's nothing safe to
+		// signal, so just wait for it to finish on its own.
+		return wrapContextErr(ctx, <-done)
+	}
+
+	interruptProcess(cmd)
+
+	grace := c.GracePeriod
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+
+	var runErr error
+	select {
+	case runErr = <-done:
+	case <-time.After(grace):
+		_ = cmd.Process.Kill()
+		runErr = <-done
+	}
+
+	return wrapContextErr(ctx, runErr)
+}
+
+func (c *ContextExecCommand) cmd() (*exec.Cmd, bool) {
+	acc, ok := c.ExecCommand.(cmdAccessor)
+	if !ok {
+		return nil, false
+	}
+	return acc.startedCmd()
+}
+
+// wrapContextErr reports why ctx ended, if it did, alongside whatever the
+// command itself returned.
+func wrapContextErr(ctx context.Context, runErr error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return runErr
+	}
+	if runErr == nil {
+		return ctxErr
+	}
+	return errors.Join(ctxErr, runErr)
+}
+
+// ExitState describes how a command run via ExecContext or
+// ExecProcessContext finished.
+type ExitState struct {
+	ExitCode int
+	Signaled bool
+	TimedOut bool
+}
+
+// ExitStateFromError derives an ExitState from the error an ExecCallback
+// receives, so callers don't have to type-assert *exec.ExitError or
+// errors.Is(context.DeadlineExceeded) themselves. A nil error reports a
+// clean exit.
+func ExitStateFromError(err error) ExitState {
+	if err == nil {
+		return ExitState{}
+	}
+
+	state := ExitState{
+		ExitCode: -1,
+		TimedOut: errors.Is(err, context.DeadlineExceeded),
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		state.ExitCode = exitErr.ExitCode()
+		state.Signaled = wasSignaled(exitErr)
+	}
+
+	return state
+}
@@ -0,0 +1,96 @@
+package tea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+type fakeExecCommand struct {
+	runErr error
+}
+
+func (f *fakeExecCommand) Run() error          { return f.runErr }
+func (f *fakeExecCommand) SetStdin(io.Reader)  {}
+func (f *fakeExecCommand) SetStdout(io.Writer) {}
+func (f *fakeExecCommand) SetStderr(io.Writer) {}
+func (f *fakeExecCommand) GetProxies() (ReaderProxy, WriterProxy, WriterProxy) {
+	return ReaderProxy{}, WriterProxy{}, WriterProxy{}
+}
+
+type fakePtyExecCommand struct {
+	fakeExecCommand
+	ptyErr error
+}
+
+func (f *fakePtyExecCommand) RunPty(io.Reader, io.Writer) error { return f.ptyErr }
+
+func TestContextExecCommandUsesPty(t *testing.T) {
+	plain := &ContextExecCommand{ExecCommand: &fakeExecCommand{}}
+	if plain.usesPty() {
+		t.Fatal("usesPty() = true for a command that doesn't support pty")
+	}
+
+	ptyWrapped := &ContextExecCommand{ExecCommand: &fakePtyExecCommand{}}
+	if !ptyWrapped.usesPty() {
+		t.Fatal("usesPty() = false for a pty-capable wrapped command")
+	}
+}
+
+func TestContextExecCommandRunPtyForwards(t *testing.T) {
+	want := errors.New("boom")
+	c := &ContextExecCommand{ExecCommand: &fakePtyExecCommand{ptyErr: want}}
+	if err := c.RunPty(nil, nil); !errors.Is(err, want) {
+		t.Fatalf("RunPty() = %v, want %v", err, want)
+	}
+}
+
+func TestContextExecCommandRunPtyRejectsNonPty(t *testing.T) {
+	c := &ContextExecCommand{ExecCommand: &fakeExecCommand{}}
+	if err := c.RunPty(nil, nil); err == nil {
+		t.Fatal("RunPty() = nil, want an error for a wrapped command that doesn't support pty")
+	}
+}
+
+// TestContextExecCommandSuperviseNoRaceOnStart cancels ctx before Run even
+// gets to call exec.Cmd.Start, so supervise's read of cmd.Process and
+// Start's write to it are racing for real rather than by coincidence of
+// scheduling. Run under go test -race, this pins supervise reading
+// cmd.Process through the same lock Start holds instead of unsynchronized.
+func TestContextExecCommandSuperviseNoRaceOnStart(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := &ContextExecCommand{
+			ExecCommand: &OsExecCommand{Cmd: exec.Command("true")},
+			ctx:         ctx,
+			GracePeriod: time.Millisecond,
+		}
+		_ = c.Run()
+	}
+}
+
+func TestExitStateFromError(t *testing.T) {
+	if got := ExitStateFromError(nil); got != (ExitState{}) {
+		t.Fatalf("ExitStateFromError(nil) = %+v, want the zero value", got)
+	}
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	state := ExitStateFromError(err)
+	if state.ExitCode != 3 {
+		t.Fatalf("ExitCode = %d, want 3", state.ExitCode)
+	}
+	if state.Signaled {
+		t.Fatal("Signaled = true, want false for a plain non-zero exit")
+	}
+
+	wrapped := fmt.Errorf("deadline: %w", context.DeadlineExceeded)
+	if got := ExitStateFromError(wrapped); !got.TimedOut {
+		t.Fatal("TimedOut = false, want true when the error wraps context.DeadlineExceeded")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package tea
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// interruptProcess asks the process to shut down gracefully.
+func interruptProcess(cmd *exec.Cmd) {
+	_ = cmd.Process.Signal(syscall.SIGINT)
+}
+
+// wasSignaled reports whether the process exited because of a signal.
+func wasSignaled(exitErr *exec.ExitError) bool {
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled()
+}
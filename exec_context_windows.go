@@ -0,0 +1,22 @@
+//go:build windows
+
+package tea
+
+import (
+	"os"
+	"os/exec"
+)
+
+// interruptProcess asks the process to shut down gracefully. Windows only
+// supports os.Interrupt for processes in the same console group, so this is
+// best-effort; if it's a no-op the GracePeriod timeout falls through to
+// Kill regardless.
+func interruptProcess(cmd *exec.Cmd) {
+	_ = cmd.Process.Signal(os.Interrupt)
+}
+
+// wasSignaled reports whether the process exited because of a signal.
+// Windows doesn't have POSIX-style signals, so this is always false.
+func wasSignaled(exitErr *exec.ExitError) bool {
+	return false
+}
@@ -0,0 +1,120 @@
+package tea
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// MultiError collects the errors Extra writers returned while a
+// ReaderProxy/WriterProxy was fanning out a chunk. An Extra failure never
+// fails the primary read/write; if that read/write returned an error of its
+// own, it is the first entry of Errs, ahead of the Extra failures.
+type MultiError struct {
+	Errs []error
+}
+
+func (m *MultiError) Error() string {
+	if len(m.Errs) == 1 {
+		return m.Errs[0].Error()
+	}
+	parts := make([]string, len(m.Errs))
+	for i, err := range m.Errs {
+		parts[i] = err.Error()
+	}
+	return "tea: multiple errors: " + strings.Join(parts, "; ")
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.Errs
+}
+
+// teeTo writes b to every extra sink, continuing past failures, and
+// returns the failures collected into a MultiError (nil if all of them
+// succeeded).
+func teeTo(extra []io.Writer, b []byte) error {
+	var me MultiError
+	for _, w := range extra {
+		if _, err := w.Write(b); err != nil {
+			me.Errs = append(me.Errs, err)
+		}
+	}
+	if len(me.Errs) == 0 {
+		return nil
+	}
+	return &me
+}
+
+// joinErrs folds an Extra-sink failure into whatever error the underlying
+// read/write already produced, so a Handler sees both without either one
+// getting silently dropped.
+func joinErrs(primary, extra error) error {
+	if primary == nil {
+		return extra
+	}
+	me, ok := extra.(*MultiError)
+	if !ok {
+		return extra
+	}
+	return &MultiError{Errs: append([]error{primary}, me.Errs...)}
+}
+
+// ByteScanner splits a ReaderProxy/WriterProxy's stream into tokens with a
+// bufio.SplitFunc before invoking the proxy's Handler, so Handler sees
+// whole lines (or whatever Split delimits) instead of raw, possibly
+// partial, buffer chunks.
+type ByteScanner struct {
+	// Split determines how the stream is tokenized. Defaults to
+	// bufio.ScanLines.
+	Split bufio.SplitFunc
+
+	buf bytes.Buffer
+}
+
+// feed appends b to the scanner's buffer and invokes handler once per
+// complete token it can split off. err, if non-nil, is reported to handler
+// after the last complete token (treating it as the final, possibly
+// partial, chunk).
+func (s *ByteScanner) feed(b []byte, err error, handler func(b []byte, n int, err error)) {
+	split := s.Split
+	if split == nil {
+		split = bufio.ScanLines
+	}
+
+	if len(b) > 0 {
+		s.buf.Write(b)
+	}
+
+	atEOF := err != nil
+	for {
+		data := s.buf.Bytes()
+		advance, token, splitErr := split(data, atEOF)
+		if splitErr != nil || advance == 0 {
+			break
+		}
+		handler(token, len(token), nil)
+		s.buf.Next(advance)
+	}
+
+	if err != nil {
+		handler(nil, 0, err)
+	}
+}
+
+// Flush hands handler whatever partial token is still buffered, then
+// discards it. A ReaderProxy's Handler always sees this automatically,
+// since feed is called with the Read error (io.EOF on a clean end) that
+// flushes the final token. A WriterProxy never sees such a terminating
+// error — Write just stops being called — so a child that exits after
+// writing an unterminated final line would otherwise have that line
+// withheld forever. Callers proxying writes with a ByteScanner should call
+// Flush once they know no more writes are coming.
+func (s *ByteScanner) Flush(handler func(b []byte, n int, err error)) {
+	if s.buf.Len() == 0 {
+		return
+	}
+	token := append([]byte(nil), s.buf.Bytes()...)
+	s.buf.Reset()
+	handler(token, len(token), nil)
+}
@@ -0,0 +1,151 @@
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestWriterProxyCallsHandlerAfterWrite pins WriterProxy's current
+// contract: the underlying Write happens first, and Handler sees the real
+// (n, err) that produced rather than the zero values it would see if
+// called beforehand.
+func TestWriterProxyCallsHandlerAfterWrite(t *testing.T) {
+	var buf bytes.Buffer
+	var gotN int
+	var gotErr error
+	var sawWriteBeforeHandler bool
+
+	wp := WriterProxy{
+		From: &buf,
+		Handler: func(b []byte, n int, err error) {
+			gotN = n
+			gotErr = err
+			sawWriteBeforeHandler = buf.Len() == len(b)
+		},
+	}
+
+	n, err := wp.Write([]byte("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("Write() = (%d, %v), want (5, nil)", n, err)
+	}
+	if gotN != 5 || gotErr != nil {
+		t.Fatalf("Handler saw (%d, %v), want (5, nil)", gotN, gotErr)
+	}
+	if !sawWriteBeforeHandler {
+		t.Fatal("Handler fired before the underlying Write committed its bytes")
+	}
+}
+
+// TestReaderProxyCallsHandlerAfterRead pins the same ordering for
+// ReaderProxy, which has always read first and reported after.
+func TestReaderProxyCallsHandlerAfterRead(t *testing.T) {
+	var gotN int
+	var gotErr error
+
+	rp := ReaderProxy{
+		From: bytes.NewReader([]byte("hi")),
+		Handler: func(b []byte, n int, err error) {
+			gotN = n
+			gotErr = err
+		},
+	}
+
+	buf := make([]byte, 2)
+	n, err := rp.Read(buf)
+	if err != nil || n != 2 {
+		t.Fatalf("Read() = (%d, %v), want (2, nil)", n, err)
+	}
+	if gotN != 2 || gotErr != nil {
+		t.Fatalf("Handler saw (%d, %v), want (2, nil)", gotN, gotErr)
+	}
+}
+
+type failWriter struct{ err error }
+
+func (f failWriter) Write(p []byte) (int, error) { return 0, f.err }
+
+func TestWriterProxyExtraFailureFoldedIntoHandlerErr(t *testing.T) {
+	var buf bytes.Buffer
+	wantErr := errors.New("disk full")
+	var gotErr error
+
+	wp := WriterProxy{
+		From:  &buf,
+		Extra: []io.Writer{failWriter{err: wantErr}},
+		Handler: func(b []byte, n int, err error) {
+			gotErr = err
+		},
+	}
+
+	if _, err := wp.Write([]byte("x")); err != nil {
+		t.Fatalf("Write() returned %v, want nil: an Extra failure shouldn't fail the write", err)
+	}
+
+	var me *MultiError
+	if !errors.As(gotErr, &me) {
+		t.Fatalf("Handler err = %v, want a *MultiError", gotErr)
+	}
+	if len(me.Errs) != 1 || !errors.Is(me.Errs[0], wantErr) {
+		t.Fatalf("MultiError.Errs = %v, want [%v]", me.Errs, wantErr)
+	}
+}
+
+func TestByteScannerSplitsLines(t *testing.T) {
+	var lines []string
+	var finalErr error
+	handler := func(b []byte, n int, err error) {
+		if err != nil {
+			finalErr = err
+			return
+		}
+		lines = append(lines, string(b))
+	}
+
+	s := &ByteScanner{}
+	s.feed([]byte("foo\nbar\nba"), nil, handler)
+	s.feed([]byte("z"), io.EOF, handler)
+
+	want := []string{"foo", "bar", "baz"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("lines = %v, want %v", lines, want)
+	}
+	if finalErr != io.EOF {
+		t.Fatalf("finalErr = %v, want io.EOF", finalErr)
+	}
+}
+
+// TestByteScannerFlushEmitsTrailingPartialToken pins the WriterProxy case
+// feed alone can't cover: Write never hands ByteScanner a terminating
+// error, so a trailing unterminated line would stay buffered forever
+// without an explicit Flush.
+func TestByteScannerFlushEmitsTrailingPartialToken(t *testing.T) {
+	var got []string
+	handler := func(b []byte, n int, err error) {
+		if err != nil {
+			t.Fatalf("handler err = %v, want nil", err)
+		}
+		got = append(got, string(b))
+	}
+
+	s := &ByteScanner{}
+	s.feed([]byte("foo\nbar"), nil, handler)
+
+	if len(got) != 1 || got[0] != "foo" {
+		t.Fatalf("before Flush, lines = %v, want [\"foo\"]", got)
+	}
+
+	s.Flush(handler)
+	want := []string{"foo", "bar"}
+	if len(got) != len(want) || got[1] != "bar" {
+		t.Fatalf("after Flush, lines = %v, want %v", got, want)
+	}
+
+	// A second Flush with nothing buffered should be a no-op.
+	s.Flush(handler)
+	if len(got) != len(want) {
+		t.Fatalf("Flush on an empty buffer called handler again: %v", got)
+	}
+}
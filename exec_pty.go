@@ -0,0 +1,87 @@
+package tea
+
+import (
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// ExecProcessPTY runs the given *exec.Cmd with a pseudo-terminal attached to
+// its stdin/stdout/stderr instead of the plain pipes ExecProcess uses, then
+// resumes the Program once the command exits. Unlike ExecProcess, the child
+// sees a real terminal: isatty checks pass, and programs such as less, git,
+// or vim that gate their behavior on having a tty work the same way they
+// would if launched directly from a shell.
+//
+// On platforms without native pty support the command falls back to the
+// same pipe-based wiring ExecProcess uses.
+//
+// stdinProxy/stdoutProxy/stderrProxy still fire on every chunk copied
+// between the pty and the program's real input/output, so callers can
+// inspect or filter traffic without disturbing the child's TTY state. Note
+// that a pty multiplexes stdout and stderr onto a single stream, so under a
+// pty stderrProxy.Handler never fires; the pipe fallback does fire it.
+func ExecProcessPTY(c *exec.Cmd, stdinProxy ReaderProxy, stdoutProxy WriterProxy, stderrProxy WriterProxy, fn ExecCallback) Cmd {
+	return Exec(&PtyExecCommand{
+		Cmd:         c,
+		StdinProxy:  stdinProxy,
+		StdoutProxy: stdoutProxy,
+		StderrProxy: stderrProxy,
+	}, fn)
+}
+
+// ptyExecCommand is implemented by ExecCommand values that manage their own
+// pseudo-terminal plumbing. Program.exec checks for this interface to
+// decide between raw pty setup and the plain pipe wiring OsExecCommand uses.
+type ptyExecCommand interface {
+	ExecCommand
+	RunPty(input io.Reader, output io.Writer) error
+}
+
+// ptyAware is implemented by ExecCommand wrappers (such as
+// ContextExecCommand) whose RunPty only exists to forward to a wrapped
+// command, and so need to report whether that wrapped command actually
+// wants the pty path. Without this, any ExecCommand satisfying
+// ptyExecCommand would look pty-capable to Program.exec even when wrapping
+// a plain OsExecCommand.
+type ptyAware interface {
+	usesPty() bool
+}
+
+// PtyExecCommand wraps an *exec.Cmd so that, when run through Program.exec,
+// the child is attached to a pseudo-terminal rather than the plain
+// ReaderProxy/WriterProxy pipes OsExecCommand uses. RunPty is implemented
+// per-platform.
+type PtyExecCommand struct {
+	*exec.Cmd
+	StdinProxy  ReaderProxy
+	StdoutProxy WriterProxy
+	StderrProxy WriterProxy
+
+	// startMu guards c.Cmd.Process against the race between RunPty
+	// starting the process and ContextExecCommand.supervise reading
+	// Process from a different goroutine; see startedCmd.
+	startMu sync.Mutex
+}
+
+func (c *PtyExecCommand) GetProxies() (ReaderProxy, WriterProxy, WriterProxy) {
+	return c.StdinProxy, c.StdoutProxy, c.StderrProxy
+}
+
+// startedCmd gives ContextExecCommand access to the underlying *exec.Cmd so
+// it can signal the process on context cancellation, reporting whether the
+// process has actually been started yet. It's synchronized against the
+// same lock RunPty holds while starting the process, so supervise never
+// reads Process while it's still being set.
+func (c *PtyExecCommand) startedCmd() (*exec.Cmd, bool) {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+	return c.Cmd, c.Cmd.Process != nil
+}
+
+// SetStdin, SetStdout and SetStderr exist to satisfy ExecCommand. They're
+// unused in the pty path: RunPty wires the child's stdio to the pty slave
+// directly instead.
+func (c *PtyExecCommand) SetStdin(io.Reader)  {}
+func (c *PtyExecCommand) SetStdout(io.Writer) {}
+func (c *PtyExecCommand) SetStderr(io.Writer) {}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package tea
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/creack/pty"
+)
+
+// RunPty starts the wrapped command attached to a pseudo-terminal, forwards
+// SIGWINCH so the child's pty stays sized to the program's real terminal,
+// and copies bytes between the pty master and input/output until the child
+// exits.
+func (c *PtyExecCommand) RunPty(input io.Reader, output io.Writer) error {
+	c.startMu.Lock()
+	f, err := pty.Start(c.Cmd)
+	c.startMu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint:errcheck
+
+	resize := func() {
+		if rows, cols, err := pty.Getsize(os.Stdout); err == nil {
+			_ = pty.Setsize(f, &pty.Winsize{Rows: uint16(rows), Cols: uint16(cols)})
+		}
+	}
+	resize()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	go func() {
+		for range winch {
+			resize()
+		}
+	}()
+
+	stdinProxy, stdoutProxy, _ := c.GetProxies()
+	stdinProxy.From = input
+	stdoutProxy.From = output
+
+	// io.Copy(f, stdinProxy) has no reason to see EOF on a normal exit, so
+	// it's deliberately not waited on here. Once the child exits we ask
+	// input to cancel its blocked Read if it supports it (see the canceler
+	// interface in exec_relay.go), so this goroutine doesn't outlive us and
+	// keep stealing keystrokes from the resumed Program; for a plain
+	// io.Reader that doesn't support cancellation, there's no portable way
+	// to interrupt it and it's left running.
+	go func() {
+		_, _ = io.Copy(f, stdinProxy)
+	}()
+	_, copyErr := io.Copy(stdoutProxy, f)
+
+	waitErr := c.Cmd.Wait()
+
+	if cr, ok := input.(canceler); ok {
+		cr.Cancel()
+	}
+
+	if waitErr != nil {
+		return waitErr
+	}
+	// The kernel returns EIO, not EOF, from the pty master once the child
+	// has closed its end — a normal part of tearing down a pty, not a real
+	// I/O failure. Treat it the same as EOF here, the way creack/pty's own
+	// examples do.
+	if copyErr != nil && copyErr != io.EOF && !errors.Is(copyErr, syscall.EIO) {
+		return copyErr
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package tea
+
+import "io"
+
+// RunPty falls back to the plain pipe wiring ExecProcess uses. Windows
+// consoles need ConPTY to give the child a real pseudo-terminal, which this
+// package doesn't bind yet, so isatty-style detection in the child won't see
+// a tty here.
+func (c *PtyExecCommand) RunPty(input io.Reader, output io.Writer) error {
+	stdinProxy, stdoutProxy, stderrProxy := c.GetProxies()
+	stdinProxy.From = input
+	stdoutProxy.From = output
+	stderrProxy.From = output
+
+	c.Cmd.Stdin = stdinProxy
+	c.Cmd.Stdout = stdoutProxy
+	c.Cmd.Stderr = stderrProxy
+
+	c.startMu.Lock()
+	err := c.Cmd.Start()
+	c.startMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.Cmd.Wait()
+}
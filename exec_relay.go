@@ -0,0 +1,131 @@
+package tea
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultIORelayTimeout is the fallback for OsExecCommand.IORelayTimeout
+// when it's left zero.
+const defaultIORelayTimeout = 5 * time.Second
+
+// ErrIORelayTimeout is returned by OsExecCommand.Run when the stdout/stderr
+// copy loops haven't finished within IORelayTimeout of the child exiting.
+var ErrIORelayTimeout = errors.New("tea: timed out waiting for i/o relay to finish")
+
+// canceler is implemented by input readers that support being interrupted
+// out of a blocked Read, such as muesli/cancelreader.Reader. Program wires
+// its real input through a canceler in normal operation; plain io.Reader
+// values are left to finish (or block forever) on their own, since there's
+// no portable way to interrupt a blocking Read.
+type canceler interface {
+	Cancel() bool
+}
+
+// cancelStdin asks the reader backing stdin to interrupt its blocked Read,
+// if it supports it. c.Cmd.Stdin is a ReaderProxy wrapping the real input
+// (see Program.exec), so the proxy itself has to be unwrapped first — the
+// proxy's own Read method never implements canceler.
+func cancelStdin(stdin io.Reader) {
+	if rp, ok := stdin.(ReaderProxy); ok {
+		stdin = rp.From
+	}
+	if cr, ok := stdin.(canceler); ok {
+		cr.Cancel()
+	}
+}
+
+// Run starts the wrapped command and relays its stdin/stdout/stderr
+// through an errgroup.Group rather than leaving exec.Cmd to manage those
+// copy goroutines itself, which it does with no timeout at all.
+//
+// The stdin copier is deliberately not part of the waited group: stdin is
+// typically the program's real input, and io.Copy(stdinPipe, stdin) has no
+// reason to ever see EOF on a normal exit, so waiting on it would block
+// every successful Run for IORelayTimeout. Instead, once the child exits,
+// we ask stdin to cancel its blocked Read if it supports it, so the copier
+// goroutine doesn't outlive us and steal keystrokes from the resumed
+// Program. IORelayTimeout guards the stdout/stderr copiers, which do see
+// EOF once the child closes its end of the pipe.
+func (c *OsExecCommand) Run() error {
+	stdin, stdout, stderr := c.Cmd.Stdin, c.Cmd.Stdout, c.Cmd.Stderr
+
+	var stdinPipe io.WriteCloser
+	var stdoutPipe, stderrPipe io.ReadCloser
+	var err error
+
+	if stdin != nil {
+		c.Cmd.Stdin = nil
+		if stdinPipe, err = c.Cmd.StdinPipe(); err != nil {
+			return err
+		}
+	}
+	if stdout != nil {
+		c.Cmd.Stdout = nil
+		if stdoutPipe, err = c.Cmd.StdoutPipe(); err != nil {
+			return err
+		}
+	}
+	if stderr != nil {
+		c.Cmd.Stderr = nil
+		if stderrPipe, err = c.Cmd.StderrPipe(); err != nil {
+			return err
+		}
+	}
+
+	c.startMu.Lock()
+	err = c.Cmd.Start()
+	c.startMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if stdinPipe != nil {
+		go func() {
+			defer stdinPipe.Close() // nolint:errcheck
+			_, _ = io.Copy(stdinPipe, stdin)
+		}()
+	}
+
+	var g errgroup.Group
+	if stdoutPipe != nil {
+		g.Go(func() error {
+			_, err := io.Copy(stdout, stdoutPipe)
+			return err
+		})
+	}
+	if stderrPipe != nil {
+		g.Go(func() error {
+			_, err := io.Copy(stderr, stderrPipe)
+			return err
+		})
+	}
+
+	runErr := c.Cmd.Wait()
+
+	cancelStdin(stdin)
+
+	relayDone := make(chan error, 1)
+	go func() { relayDone <- g.Wait() }()
+
+	timeout := c.IORelayTimeout
+	if timeout <= 0 {
+		timeout = defaultIORelayTimeout
+	}
+
+	select {
+	case relayErr := <-relayDone:
+		if runErr != nil {
+			return runErr
+		}
+		return relayErr
+	case <-time.After(timeout):
+		if runErr != nil {
+			return runErr
+		}
+		return ErrIORelayTimeout
+	}
+}
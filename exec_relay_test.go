@@ -0,0 +1,56 @@
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// blockingReader never returns from Read, simulating a terminal read that's
+// still parked waiting for a keystroke after the child has already exited.
+type blockingReader struct{}
+
+func (blockingReader) Read([]byte) (int, error) {
+	select {}
+}
+
+func TestOsExecCommandRunCleanExitDoesNotWaitOnStdin(t *testing.T) {
+	c := &OsExecCommand{Cmd: exec.Command("true")}
+	c.Cmd.Stdin = blockingReader{}
+	c.Cmd.Stdout = &bytes.Buffer{}
+	c.Cmd.Stderr = &bytes.Buffer{}
+
+	start := time.Now()
+	err := c.Run()
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Run() returned %v, want nil", err)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("Run() took %s; it should return promptly despite the blocked stdin read", elapsed)
+	}
+}
+
+// blockingWriter never returns from Write, simulating a stuck downstream
+// consumer of the child's stdout.
+type blockingWriter struct{}
+
+func (blockingWriter) Write([]byte) (int, error) {
+	select {}
+}
+
+func TestOsExecCommandRunStdoutTimeout(t *testing.T) {
+	c := &OsExecCommand{
+		Cmd:            exec.Command("sh", "-c", "echo hi"),
+		IORelayTimeout: 50 * time.Millisecond,
+	}
+	c.Cmd.Stdout = blockingWriter{}
+
+	err := c.Run()
+	if !errors.Is(err, ErrIORelayTimeout) {
+		t.Fatalf("Run() returned %v, want ErrIORelayTimeout", err)
+	}
+}
@@ -0,0 +1,191 @@
+package tea
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// ProcessStdoutMsg is sent for each chunk of stdout produced by a process
+// started with SpawnProcess, chunked according to SpawnOptions.Split.
+type ProcessStdoutMsg struct {
+	PID  int
+	Data []byte
+}
+
+// ProcessStderrMsg is the stderr counterpart to ProcessStdoutMsg.
+type ProcessStderrMsg struct {
+	PID  int
+	Data []byte
+}
+
+// ProcessExitMsg is sent after a process started with SpawnProcess exits or
+// fails to start. Err is nil on a clean zero-status exit. SendStdin and
+// KillProcess also return a ProcessExitMsg, with ExitCode -1, when they
+// fail, so a pid can receive more than one.
+type ProcessExitMsg struct {
+	PID      int
+	Err      error
+	ExitCode int
+}
+
+// SpawnOptions configures SpawnProcess.
+type SpawnOptions struct {
+	// Split controls how stdout/stderr are chunked into messages. Defaults
+	// to SplitLines{}.
+	Split SplitMode
+}
+
+// spawnedProcess tracks a process started by SpawnProcess so SendStdin,
+// KillProcess and ListenProcess can reach it by pid.
+type spawnedProcess struct {
+	pid   int
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	msgs  chan Msg
+}
+
+var (
+	processesMu sync.Mutex
+	processes   = map[int]*spawnedProcess{}
+)
+
+// SpawnProcess starts c in the background without releasing the terminal,
+// then streams its output back into the Update loop as ProcessStdoutMsg,
+// ProcessStderrMsg and, on exit, ProcessExitMsg. Unlike Exec/ExecProcess the
+// Program keeps running while the child is alive; drive it further with
+// SendStdin and KillProcess.
+//
+// The returned Cmd delivers the process's first message. To keep
+// streaming, have Update return ListenProcess(pid) again whenever it
+// receives a Process*Msg for that pid; the ProcessExitMsg sent on exit is
+// always the last message ListenProcess yields for a given pid.
+func SpawnProcess(c *exec.Cmd, opts SpawnOptions) Cmd {
+	return func() Msg {
+		p, err := startProcess(c, opts)
+		if err != nil {
+			return ProcessExitMsg{Err: err, ExitCode: -1}
+		}
+		return ListenProcess(p.pid)()
+	}
+}
+
+func startProcess(c *exec.Cmd, opts SpawnOptions) (*spawnedProcess, error) {
+	split := opts.Split
+	if split == nil {
+		split = SplitLines{}
+	}
+
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := c.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &spawnedProcess{
+		pid:   c.Process.Pid,
+		cmd:   c,
+		stdin: stdin,
+		msgs:  make(chan Msg),
+	}
+
+	processesMu.Lock()
+	processes[p.pid] = p
+	processesMu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		split.split(stdout, func(b []byte) { p.msgs <- ProcessStdoutMsg{PID: p.pid, Data: b} })
+	}()
+	go func() {
+		defer wg.Done()
+		split.split(stderr, func(b []byte) { p.msgs <- ProcessStderrMsg{PID: p.pid, Data: b} })
+	}()
+
+	go func() {
+		wg.Wait()
+		waitErr := c.Wait()
+
+		exitCode := 0
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else if waitErr != nil {
+			exitCode = -1
+		}
+		p.msgs <- ProcessExitMsg{PID: p.pid, Err: waitErr, ExitCode: exitCode}
+
+		processesMu.Lock()
+		delete(processes, p.pid)
+		processesMu.Unlock()
+		close(p.msgs)
+	}()
+
+	return p, nil
+}
+
+// ListenProcess waits for the next message from the process with the given
+// pid. See SpawnProcess for the streaming pattern this is meant to drive.
+func ListenProcess(pid int) Cmd {
+	return func() Msg {
+		p := lookupProcess(pid)
+		if p == nil {
+			return nil
+		}
+		msg, ok := <-p.msgs
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// SendStdin writes data to the stdin of the process with the given pid.
+func SendStdin(pid int, data []byte) Cmd {
+	return func() Msg {
+		p := lookupProcess(pid)
+		if p == nil {
+			return ProcessExitMsg{PID: pid, Err: fmt.Errorf("tea: no such process: %d", pid), ExitCode: -1}
+		}
+		if _, err := p.stdin.Write(data); err != nil {
+			return ProcessExitMsg{PID: pid, Err: err, ExitCode: -1}
+		}
+		return nil
+	}
+}
+
+// KillProcess sends sig to the process with the given pid. Use
+// os.Interrupt or a stronger signal such as syscall.SIGKILL depending on
+// how forcefully you want to stop it.
+func KillProcess(pid int, sig os.Signal) Cmd {
+	return func() Msg {
+		p := lookupProcess(pid)
+		if p == nil {
+			return nil
+		}
+		if err := p.cmd.Process.Signal(sig); err != nil {
+			return ProcessExitMsg{PID: pid, Err: err, ExitCode: -1}
+		}
+		return nil
+	}
+}
+
+func lookupProcess(pid int) *spawnedProcess {
+	processesMu.Lock()
+	defer processesMu.Unlock()
+	return processes[pid]
+}
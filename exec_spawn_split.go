@@ -0,0 +1,106 @@
+package tea
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// SplitMode controls how SpawnProcess chunks a child's stdout/stderr before
+// emitting ProcessStdoutMsg/ProcessStderrMsg.
+type SplitMode interface {
+	// split reads from r until EOF, invoking emit with each chunk it
+	// produces. It returns once r is exhausted.
+	split(r io.Reader, emit func([]byte))
+}
+
+// SplitLines emits one message per line, using bufio.Scanner's ScanLines
+// split function. This is the default when SpawnOptions.Split is left
+// unset.
+type SplitLines struct{}
+
+func (SplitLines) split(r io.Reader, emit func([]byte)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		emit(line)
+	}
+	// The scanner stops early on a line over its 1 MiB limit; drain the
+	// rest without emitting it so r is still read to EOF.
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// SplitChars emits one message per byte. Useful for interactive prompts
+// that expect input before ending a line, where SplitLines would never
+// flush.
+type SplitChars struct{}
+
+func (SplitChars) split(r io.Reader, emit func([]byte)) {
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			emit([]byte{buf[0]})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// SplitTime accumulates bytes and flushes whatever's been read every
+// Interval, so slow-producing children still surface output instead of
+// waiting on a line break or EOF. Interval defaults to 250ms when zero.
+type SplitTime struct {
+	Interval time.Duration
+}
+
+func (s SplitTime) split(r io.Reader, emit func([]byte)) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+
+	var mu sync.Mutex
+	var buf []byte
+	flush := func() {
+		mu.Lock()
+		chunk := buf
+		buf = nil
+		mu.Unlock()
+		if len(chunk) > 0 {
+			emit(chunk)
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				flush()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	readBuf := make([]byte, 4096)
+	for {
+		n, err := r.Read(readBuf)
+		if n > 0 {
+			mu.Lock()
+			buf = append(buf, readBuf[:n]...)
+			mu.Unlock()
+		}
+		if err != nil {
+			flush()
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package tea
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func collect(split SplitMode, r io.Reader) [][]byte {
+	var got [][]byte
+	split.split(r, func(b []byte) {
+		got = append(got, append([]byte(nil), b...))
+	})
+	return got
+}
+
+func TestSplitLines(t *testing.T) {
+	got := collect(SplitLines{}, strings.NewReader("foo\nbar\nbaz"))
+	want := []string{"foo", "bar", "baz"}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %q", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if string(got[i]) != w {
+			t.Fatalf("chunk %d = %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+func TestSplitChars(t *testing.T) {
+	got := collect(SplitChars{}, strings.NewReader("ab"))
+	if len(got) != 2 || string(got[0]) != "a" || string(got[1]) != "b" {
+		t.Fatalf("got %q, want one chunk per byte: [\"a\" \"b\"]", got)
+	}
+}
+
+func TestSplitTimeFlushesOnIntervalAndEOF(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	var mu sync.Mutex
+	var chunks [][]byte
+	emit := func(b []byte) {
+		mu.Lock()
+		chunks = append(chunks, append([]byte(nil), b...))
+		mu.Unlock()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		(SplitTime{Interval: 20 * time.Millisecond}).split(pr, emit)
+	}()
+
+	if _, err := pw.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	// Give the ticker a few intervals to fire before we close, so the
+	// bytes are flushed on the timer rather than only on EOF.
+	time.Sleep(60 * time.Millisecond)
+	mu.Lock()
+	flushedBeforeEOF := len(chunks)
+	mu.Unlock()
+	_ = pw.Close()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if flushedBeforeEOF == 0 {
+		t.Fatal("expected at least one chunk to be flushed before EOF")
+	}
+
+	var all []byte
+	for _, c := range chunks {
+		all = append(all, c...)
+	}
+	if string(all) != "hello" {
+		t.Fatalf("reassembled chunks = %q, want %q", all, "hello")
+	}
+}